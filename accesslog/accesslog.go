@@ -0,0 +1,209 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package accesslog provides an http.Handler middleware that emits one
+// access log line per request through glog, at severity INFO.
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dnephin/glog"
+)
+
+// Format selects the on-wire representation of an access log line.
+type Format int
+
+const (
+	// CommonFormat renders lines in the Apache Common Log Format.
+	CommonFormat Format = iota
+	// CombinedFormat renders lines in the Apache Combined Log Format,
+	// which adds the Referer and User-Agent headers to CommonFormat.
+	CombinedFormat
+	// JSONFormat renders lines as a single-line JSON object.
+	JSONFormat
+)
+
+// AccessOptions configures Handler.
+type AccessOptions struct {
+	// Format selects the line format. The zero value is CommonFormat.
+	Format Format
+}
+
+// AccessLogRecord describes a single completed HTTP request.
+type AccessLogRecord struct {
+	RemoteAddr  string
+	Method      string
+	RequestURI  string
+	Proto       string
+	Status      int
+	BodyBytes   int64
+	Elapsed     time.Duration
+	UserAgent   string
+	Referer     string
+	RequestTime time.Time
+}
+
+// Handler wraps next so that every request it serves produces one
+// AccessLogRecord, logged through glog.Output at severity INFO once the
+// request completes.
+func Handler(next http.Handler, opts AccessOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		rec := AccessLogRecord{
+			RemoteAddr:  r.RemoteAddr,
+			Method:      r.Method,
+			RequestURI:  r.RequestURI,
+			Proto:       r.Proto,
+			Status:      sw.status,
+			BodyBytes:   sw.bytes,
+			Elapsed:     time.Since(start),
+			UserAgent:   r.UserAgent(),
+			Referer:     r.Referer(),
+			RequestTime: start,
+		}
+		formatRecord(opts.Format, rec)
+	})
+}
+
+// statusWriter captures the status code and byte count of a response,
+// while forwarding the optional http.Flusher, http.Hijacker and
+// http.Pusher interfaces of the wrapped ResponseWriter so that handlers
+// doing streaming, websocket upgrades or HTTP/2 push keep working.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("accesslog: underlying ResponseWriter does not support http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+func (w *statusWriter) Push(target string, opts *http.PushOptions) error {
+	p, ok := w.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return p.Push(target, opts)
+}
+
+// bufferPool reuses buffers across requests. A buffer is handed straight
+// to glog.Output, which writes it synchronously, and is only returned to
+// the pool once Output returns, so formatting a line needs no extra copy.
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func formatRecord(f Format, rec AccessLogRecord) {
+	switch f {
+	case JSONFormat:
+		outputJSON(rec)
+	case CombinedFormat:
+		outputApache(rec, true)
+	default:
+		outputApache(rec, false)
+	}
+}
+
+func outputApache(rec AccessLogRecord, combined bool) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+	buf.Reset()
+
+	fmt.Fprintf(buf, "%s - - [%s] %q %d %d",
+		rec.RemoteAddr,
+		rec.RequestTime.Format("02/Jan/2006:15:04:05 -0700"),
+		fmt.Sprintf("%s %s %s", rec.Method, rec.RequestURI, rec.Proto),
+		rec.Status,
+		rec.BodyBytes,
+	)
+	if combined {
+		fmt.Fprintf(buf, " %q %q", rec.Referer, rec.UserAgent)
+	}
+	buf.WriteByte('\n')
+	glog.Output(glog.SeverityInfo, buf.Bytes())
+}
+
+// jsonRecord mirrors AccessLogRecord's fields so outputJSON can marshal a
+// struct straight into the pooled buffer instead of boxing the values
+// into a map[string]interface{} first.
+type jsonRecord struct {
+	RemoteAddr string  `json:"remote_addr"`
+	Method     string  `json:"method"`
+	RequestURI string  `json:"request_uri"`
+	Proto      string  `json:"proto"`
+	Status     int     `json:"status"`
+	BodyBytes  int64   `json:"body_bytes"`
+	ElapsedMs  float64 `json:"elapsed_ms"`
+	UserAgent  string  `json:"user_agent"`
+	Referer    string  `json:"referer"`
+	Ts         string  `json:"ts"`
+}
+
+func outputJSON(rec AccessLogRecord) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+	buf.Reset()
+
+	err := json.NewEncoder(buf).Encode(jsonRecord{
+		RemoteAddr: rec.RemoteAddr,
+		Method:     rec.Method,
+		RequestURI: rec.RequestURI,
+		Proto:      rec.Proto,
+		Status:     rec.Status,
+		BodyBytes:  rec.BodyBytes,
+		ElapsedMs:  float64(rec.Elapsed) / float64(time.Millisecond),
+		UserAgent:  rec.UserAgent,
+		Referer:    rec.Referer,
+		Ts:         rec.RequestTime.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		buf.Reset()
+		fmt.Fprintf(buf, "{\"msg\":\"accesslog: failed to marshal record: %s\"}\n", err)
+	}
+	glog.Output(glog.SeverityInfo, buf.Bytes())
+}
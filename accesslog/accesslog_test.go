@@ -0,0 +1,91 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnephin/glog"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func setupBuffer() (*bytes.Buffer, func()) {
+	buf := new(bytes.Buffer)
+	glog.Init(glog.Options{Output: buf})
+	return buf, func() { glog.Init(glog.Options{}) }
+}
+
+func TestHandler_CommonFormat(t *testing.T) {
+	buf, teardown := setupBuffer()
+	defer teardown()
+	srv := httptest.NewServer(Handler(okHandler(), AccessOptions{Format: CommonFormat}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/hello")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	out := buf.String()
+	assert.Check(t, is.Contains(out, `"GET /hello HTTP/1.1"`))
+	assert.Check(t, is.Contains(out, " 200 2"))
+}
+
+func TestHandler_CombinedFormat(t *testing.T) {
+	buf, teardown := setupBuffer()
+	defer teardown()
+	srv := httptest.NewServer(Handler(okHandler(), AccessOptions{Format: CombinedFormat}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/hello", nil)
+	assert.NilError(t, err)
+	req.Header.Set("Referer", "http://example.com")
+	req.Header.Set("User-Agent", "glog-test")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	out := buf.String()
+	assert.Check(t, is.Contains(out, `"http://example.com"`))
+	assert.Check(t, is.Contains(out, `"glog-test"`))
+}
+
+func TestHandler_JSONFormat(t *testing.T) {
+	buf, teardown := setupBuffer()
+	defer teardown()
+	srv := httptest.NewServer(Handler(okHandler(), AccessOptions{Format: JSONFormat}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/hello")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	out := buf.String()
+	assert.Check(t, is.Contains(out, `"status":200`))
+	assert.Check(t, is.Contains(out, `"request_uri":"/hello"`))
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,107 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ServeAdmin registers handlers under prefix on mux for runtime inspection
+// and mutation of glog's verbosity settings:
+//
+//	GET  prefix/vmodule  returns the current vmodule spec
+//	PUT  prefix/vmodule  replaces it with the request body, eg "pkg=3,other/*=1"
+//	GET  prefix/v        returns the current global verbosity level
+//	PUT  prefix/v        sets it from the request body, eg "2"
+//	GET  prefix/stats    returns per-severity line counts as JSON
+//	POST prefix/stack    dumps goroutine stacks; ?all=true for every goroutine
+//
+// prefix should not have a trailing slash, eg "/debug/glog".
+func ServeAdmin(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc(prefix+"/vmodule", handleVmodule)
+	mux.HandleFunc(prefix+"/v", handleV)
+	mux.HandleFunc(prefix+"/stats", handleStats)
+	mux.HandleFunc(prefix+"/stack", handleStack)
+}
+
+func handleVmodule(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = w.Write([]byte(VmoduleString()))
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := SetVmodule(string(body)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleV(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = w.Write([]byte(strconv.Itoa(int(Verbosity()))))
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(string(body)))
+		if err != nil {
+			http.Error(w, "invalid verbosity level: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		SetVerbosity(Level(level))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Stats())
+}
+
+func handleStack(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	_, _ = w.Write(stacks(all))
+}
@@ -0,0 +1,121 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func setupAdmin(t *testing.T) (*httptest.Server, func()) {
+	mux := http.NewServeMux()
+	ServeAdmin(mux, "/debug/glog")
+	srv := httptest.NewServer(mux)
+	oldLevel := Verbosity()
+	return srv, func() {
+		srv.Close()
+		SetVerbosity(oldLevel)
+	}
+}
+
+func TestServeAdminSetVerbosity(t *testing.T) {
+	buf, teardown := setupBuffer()
+	defer teardown()
+	srv, adminTeardown := setupAdmin(t)
+	defer adminTeardown()
+
+	V(2).Info("hidden")
+	assert.Assert(t, buf.Len() == 0)
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/debug/glog/v", strings.NewReader("2"))
+	assert.NilError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusNoContent)
+
+	V(2).Info("visible")
+	assert.Assert(t, is.Contains(buf.String(), "visible"))
+}
+
+func TestServeAdminGetV(t *testing.T) {
+	srv, teardown := setupAdmin(t)
+	defer teardown()
+
+	SetVerbosity(3)
+	resp, err := http.Get(srv.URL + "/debug/glog/v")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, readBody(t, resp), "3")
+}
+
+func TestServeAdminVmodule(t *testing.T) {
+	srv, teardown := setupAdmin(t)
+	defer teardown()
+	defer func() { assert.NilError(t, SetVmodule("")) }()
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/debug/glog/vmodule", strings.NewReader("admin_test=2"))
+	assert.NilError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusNoContent)
+	assert.Equal(t, VmoduleString(), "admin_test=2")
+
+	getResp, err := http.Get(srv.URL + "/debug/glog/vmodule")
+	assert.NilError(t, err)
+	defer getResp.Body.Close()
+	assert.Equal(t, readBody(t, getResp), "admin_test=2")
+}
+
+func TestServeAdminStats(t *testing.T) {
+	buf, teardown := setupBuffer()
+	defer teardown()
+	srv, adminTeardown := setupAdmin(t)
+	defer adminTeardown()
+
+	Info("counted")
+	_ = buf
+
+	resp, err := http.Get(srv.URL + "/debug/glog/stats")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Assert(t, is.Contains(readBody(t, resp), `"INFO"`))
+}
+
+func TestServeAdminStack(t *testing.T) {
+	srv, teardown := setupAdmin(t)
+	defer teardown()
+
+	resp, err := http.Post(srv.URL+"/debug/glog/stack", "text/plain", nil)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Assert(t, is.Contains(readBody(t, resp), "goroutine"))
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return string(b)
+}
@@ -0,0 +1,157 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Field is a single key/value pair contributed by a ContextExtractor. A
+// Formatter is responsible for rendering Fields alongside the log message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// ContextExtractor derives Fields from a context.Context. Extractors run on
+// every *Context logging call (InfoContext, ErrorContext, and so on); their
+// Fields are appended to the line in addition to the logged message.
+type ContextExtractor func(ctx context.Context) []Field
+
+var (
+	extractorsMu sync.Mutex
+	extractors   []ContextExtractor
+)
+
+// RegisterContextExtractor adds e to the set of extractors run by every
+// *Context logging call. Extractors are run in the order they were
+// registered. RegisterContextExtractor is typically called from an init
+// function.
+func RegisterContextExtractor(e ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, e)
+}
+
+// contextFields runs every registered ContextExtractor against ctx and
+// collects their Fields.
+func contextFields(ctx context.Context) []Field {
+	extractorsMu.Lock()
+	fns := make([]ContextExtractor, len(extractors))
+	copy(fns, extractors)
+	extractorsMu.Unlock()
+
+	var fields []Field
+	for _, fn := range fns {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+// printContext, printDepthContext, and printfContext mirror print,
+// printDepth, and printf, but also attach the Fields extracted from ctx.
+func (l *loggingT) printContext(s severity, ctx context.Context, args ...interface{}) {
+	l.printDepthContext(s, ctx, 1, args...)
+}
+
+func (l *loggingT) printDepthContext(s severity, ctx context.Context, depth int, args ...interface{}) {
+	file, line := callerFileLine(depth)
+	l.write(s, l.formatter.Format(s, file, line, timeNow(), pid, []byte(fmt.Sprint(args...)), contextFields(ctx)...))
+}
+
+func (l *loggingT) printfContext(s severity, ctx context.Context, format string, args ...interface{}) {
+	file, line := callerFileLine(0)
+	l.write(s, l.formatter.Format(s, file, line, timeNow(), pid, []byte(fmt.Sprintf(format, args...)), contextFields(ctx)...))
+}
+
+// InfoContext acts as Info, and additionally logs the Fields extracted from
+// ctx by any registered ContextExtractor.
+func InfoContext(ctx context.Context, args ...interface{}) {
+	logging.printContext(infoLog, ctx, args...)
+}
+
+// InfoDepthContext acts as InfoDepth, and additionally logs the Fields
+// extracted from ctx.
+func InfoDepthContext(ctx context.Context, depth int, args ...interface{}) {
+	logging.printDepthContext(infoLog, ctx, depth, args...)
+}
+
+// InfofContext acts as Infof, and additionally logs the Fields extracted
+// from ctx.
+func InfofContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printfContext(infoLog, ctx, format, args...)
+}
+
+// WarningContext acts as Warning, and additionally logs the Fields
+// extracted from ctx.
+func WarningContext(ctx context.Context, args ...interface{}) {
+	logging.printContext(warningLog, ctx, args...)
+}
+
+// WarningDepthContext acts as WarningDepth, and additionally logs the
+// Fields extracted from ctx.
+func WarningDepthContext(ctx context.Context, depth int, args ...interface{}) {
+	logging.printDepthContext(warningLog, ctx, depth, args...)
+}
+
+// WarningfContext acts as Warningf, and additionally logs the Fields
+// extracted from ctx.
+func WarningfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printfContext(warningLog, ctx, format, args...)
+}
+
+// ErrorContext acts as Error, and additionally logs the Fields extracted
+// from ctx.
+func ErrorContext(ctx context.Context, args ...interface{}) {
+	logging.printContext(errorLog, ctx, args...)
+}
+
+// ErrorDepthContext acts as ErrorDepth, and additionally logs the Fields
+// extracted from ctx.
+func ErrorDepthContext(ctx context.Context, depth int, args ...interface{}) {
+	logging.printDepthContext(errorLog, ctx, depth, args...)
+}
+
+// ErrorfContext acts as Errorf, and additionally logs the Fields extracted
+// from ctx.
+func ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printfContext(errorLog, ctx, format, args...)
+}
+
+// FatalContext acts as Fatal, and additionally logs the Fields extracted
+// from ctx, then calls os.Exit(1).
+func FatalContext(ctx context.Context, args ...interface{}) {
+	logging.printContext(fatalLog, ctx, args...)
+	os.Exit(1)
+}
+
+// FatalDepthContext acts as FatalDepth, and additionally logs the Fields
+// extracted from ctx, then calls os.Exit(1).
+func FatalDepthContext(ctx context.Context, depth int, args ...interface{}) {
+	logging.printDepthContext(fatalLog, ctx, depth, args...)
+	os.Exit(1)
+}
+
+// FatalfContext acts as Fatalf, and additionally logs the Fields extracted
+// from ctx, then calls os.Exit(1).
+func FatalfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printfContext(fatalLog, ctx, format, args...)
+	os.Exit(1)
+}
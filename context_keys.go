@@ -0,0 +1,68 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import "context"
+
+// contextKey namespaces the values glog stores on a context.Context, so
+// they don't collide with keys used by other packages.
+type contextKey string
+
+const (
+	traceIDKey   contextKey = "trace_id"
+	spanIDKey    contextKey = "span_id"
+	requestIDKey contextKey = "request_id"
+)
+
+// WithTraceID returns a copy of ctx carrying id, which the built-in
+// correlation extractor logs as "trace_id" on every *Context call.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// WithSpanID returns a copy of ctx carrying id, which the built-in
+// correlation extractor logs as "span_id" on every *Context call.
+func WithSpanID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, spanIDKey, id)
+}
+
+// WithRequestID returns a copy of ctx carrying id, which the built-in
+// correlation extractor logs as "request_id" on every *Context call.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func init() {
+	RegisterContextExtractor(correlationIDFields)
+}
+
+// correlationIDFields is the built-in ContextExtractor for the trace_id,
+// span_id, and request_id keys set by WithTraceID, WithSpanID, and
+// WithRequestID.
+func correlationIDFields(ctx context.Context) []Field {
+	var fields []Field
+	if v, ok := ctx.Value(traceIDKey).(string); ok && v != "" {
+		fields = append(fields, Field{Key: "trace_id", Value: v})
+	}
+	if v, ok := ctx.Value(spanIDKey).(string); ok && v != "" {
+		fields = append(fields, Field{Key: "span_id", Value: v})
+	}
+	if v, ok := ctx.Value(requestIDKey).(string); ok && v != "" {
+		fields = append(fields, Field{Key: "request_id", Value: v})
+	}
+	return fields
+}
@@ -0,0 +1,71 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+// Test that InfoContext logs the Fields contributed by a registered
+// ContextExtractor, for every built-in Formatter.
+func TestInfoContext(t *testing.T) {
+	for _, tc := range formatters {
+		t.Run(tc.name, func(t *testing.T) {
+			buf, teardown := setupBufferWithFormatter(tc.Formatter)
+			defer teardown()
+
+			ctx := WithTraceID(context.Background(), "abc123")
+			InfoContext(ctx, "test")
+			out := buf.String()
+
+			assert.Check(t, is.Contains(out, "context_test.go"))
+			switch tc.name {
+			case "glog", "logfmt":
+				assert.Check(t, is.Contains(out, "trace_id=abc123"))
+			case "json":
+				assert.Check(t, is.Contains(out, `"trace_id":"abc123"`))
+			}
+		})
+	}
+}
+
+// Test that a custom ContextExtractor's Fields are logged alongside the
+// message, analogous to TestInfoDepth.
+func TestRegisterContextExtractor(t *testing.T) {
+	type userIDKey struct{}
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		id, ok := ctx.Value(userIDKey{}).(int)
+		if !ok {
+			return nil
+		}
+		return []Field{{Key: "user_id", Value: id}}
+	})
+
+	buf, teardown := setupBuffer()
+	defer teardown()
+
+	ctx := context.WithValue(context.Background(), userIDKey{}, 42)
+	InfoContext(ctx, "depth-test")
+
+	out := buf.String()
+	assert.Check(t, is.Contains(out, "depth-test"))
+	assert.Check(t, is.Contains(out, "user_id=42"))
+}
@@ -0,0 +1,109 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Formatter renders a single log line. Implementations are given the
+// severity, the caller's file and line, the time the line was logged, the
+// process ID, the formatted message, and any Fields contributed by a
+// registered ContextExtractor, and return the bytes to write to
+// Options.Output, including any trailing newline.
+//
+// Formatter implementations must be safe for concurrent use.
+type Formatter interface {
+	Format(sev Severity, file string, line int, t time.Time, pid int, msg []byte, fields ...Field) []byte
+}
+
+// GlogFormatter reproduces the classic glog header:
+//
+//	Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg
+//
+// It is the default Formatter when Options.Formatter is unset.
+type GlogFormatter struct{}
+
+// Format implements Formatter.
+func (GlogFormatter) Format(sev Severity, file string, line int, t time.Time, pid int, msg []byte, fields ...Field) []byte {
+	buf := logging.getBuffer()
+	logging.writeGlogHeader(buf, sev, file, line, t, pid)
+	buf.Write(trimNewlineBytes(msg))
+	for _, f := range fields {
+		fmt.Fprintf(buf, " %s=%v", f.Key, f.Value)
+	}
+	buf.WriteByte('\n')
+	out := append([]byte(nil), buf.Bytes()...)
+	logging.putBuffer(buf)
+	return out
+}
+
+// JSONFormatter renders each log line as a single-line JSON object with
+// "level", "ts", "caller", "msg", and "pid" fields.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(sev Severity, file string, line int, t time.Time, pid int, msg []byte, fields ...Field) []byte {
+	rec := map[string]interface{}{
+		"level":  sev.String(),
+		"ts":     t.Format(time.RFC3339Nano),
+		"caller": fmt.Sprintf("%s:%d", file, line),
+		"msg":    trimNewline(string(msg)),
+		"pid":    pid,
+	}
+	for _, f := range fields {
+		rec[f.Key] = f.Value
+	}
+	out, err := json.Marshal(rec)
+	if err != nil {
+		out = []byte(fmt.Sprintf(`{"level":%q,"msg":"glog: failed to marshal log line: %s"}`, sev.String(), err))
+	}
+	return append(out, '\n')
+}
+
+// LogfmtFormatter renders each log line in logfmt (key=value) form.
+type LogfmtFormatter struct{}
+
+// Format implements Formatter.
+func (LogfmtFormatter) Format(sev Severity, file string, line int, t time.Time, pid int, msg []byte, fields ...Field) []byte {
+	buf := logging.getBuffer()
+	fmt.Fprintf(buf, "level=%s ts=%s caller=%s:%d pid=%d msg=%q",
+		sev.String(), t.Format(time.RFC3339Nano), file, line, pid, trimNewline(string(msg)))
+	for _, f := range fields {
+		fmt.Fprintf(buf, " %s=%v", f.Key, f.Value)
+	}
+	buf.WriteByte('\n')
+	out := append([]byte(nil), buf.Bytes()...)
+	logging.putBuffer(buf)
+	return out
+}
+
+func trimNewline(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+func trimNewlineBytes(b []byte) []byte {
+	if len(b) > 0 && b[len(b)-1] == '\n' {
+		return b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,518 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glog implements logging analogous to the Google-internal C++
+// INFO/ERROR logging library. It provides functions Info, Warning, Error,
+// Fatal, plus formatting variants such as Infof.
+//
+// Unlike the original glog, configuration is performed by calling Init with
+// an Options value rather than through command line flags, which makes the
+// package safe to use as a library.
+package glog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// severity identifies the sort of log: info, warning etc.
+type severity int32
+
+const (
+	infoLog severity = iota
+	warningLog
+	errorLog
+	fatalLog
+	numSeverity = 4
+)
+
+const severityChar = "IWEF"
+
+var severityName = []string{
+	infoLog:    "INFO",
+	warningLog: "WARNING",
+	errorLog:   "ERROR",
+	fatalLog:   "FATAL",
+}
+
+// timeNow is used for testing.
+var timeNow = time.Now
+
+// pid is cached at startup, and overridable in tests.
+var pid = os.Getpid()
+
+// Severity is the exported name for the log severity levels, for use by
+// Formatter implementations outside this package.
+type Severity = severity
+
+// The severity levels, exported for packages that call Output directly,
+// such as glog/accesslog.
+const (
+	SeverityInfo    Severity = infoLog
+	SeverityWarning Severity = warningLog
+	SeverityError   Severity = errorLog
+	SeverityFatal   Severity = fatalLog
+)
+
+// String returns the name of the severity, eg "INFO".
+func (s severity) String() string {
+	if s < 0 || int(s) >= len(severityName) {
+		return "UNKNOWN"
+	}
+	return severityName[s]
+}
+
+// Options configures the glog package. It is passed to Init.
+type Options struct {
+	// Output is the writer that log lines are written to. If nil,
+	// os.Stderr is used.
+	Output io.Writer
+
+	// Formatter controls how a log line is rendered before it is written
+	// to Output. If nil, GlogFormatter is used, which reproduces the
+	// classic glog header format.
+	Formatter Formatter
+
+	// File, when Path is set, routes log output through a rotating file
+	// writer instead of Output.
+	File FileOptions
+
+	// ReopenOnSignal, when File.Path is set, causes the log file to be
+	// rotated whenever the process receives SIGHUP.
+	ReopenOnSignal bool
+}
+
+// Init configures the glog package from opts. It replaces any previous
+// configuration, stopping any SIGHUP watcher started by a prior call to
+// Init. Init is not safe to call concurrently with logging calls.
+//
+// Init returns a handle for flushing and releasing the configured output.
+// Callers that set Options.File should defer handle.Close() before exiting;
+// the handle is always non-nil, even when no file rotation was configured.
+func Init(opts Options) SyncCloser {
+	logging.mu.Lock()
+	defer logging.mu.Unlock()
+
+	if logging.stopReopen != nil {
+		logging.stopReopen()
+		logging.stopReopen = nil
+	}
+
+	var handle SyncCloser = noopSyncCloser{}
+	out := opts.Output
+	if opts.File.Path != "" {
+		lj, rf := newRotatingOutput(opts.File)
+		out = lj
+		handle = rf
+		if opts.ReopenOnSignal {
+			logging.stopReopen = watchReopen(lj)
+		}
+	} else if out == nil {
+		out = os.Stderr
+	}
+
+	formatter := opts.Formatter
+	if formatter == nil {
+		formatter = GlogFormatter{}
+	}
+
+	logging.out = out
+	logging.formatter = formatter
+	return handle
+}
+
+func init() {
+	Init(Options{Output: os.Stderr})
+}
+
+// buffer holds a byte Buffer for reuse. The zero value is ready to use.
+type buffer struct {
+	bytes.Buffer
+	tmp  [64]byte // temporary byte array for creating headers.
+	next *buffer
+}
+
+// loggingT collects all the global state of the logging setup.
+type loggingT struct {
+	mu sync.Mutex
+
+	// out is the destination for all log lines, set by Init.
+	out io.Writer
+
+	// formatter renders a log line before it is written to out, set by
+	// Init.
+	formatter Formatter
+
+	// stopReopen cancels the SIGHUP watcher started by the previous call
+	// to Init, if any.
+	stopReopen func()
+
+	// stats counts the lines written at each severity, indexed by
+	// severity. It is read by Stats and updated atomically so write does
+	// not need to hold mu for the increment.
+	stats [numSeverity]int64
+
+	// freeList is a list of byte buffers, maintained under freeListMu.
+	freeList *buffer
+	// freeListMu maintains the free list. It is separate from the main mutex
+	// so buffers can be grabbed and printed to without holding the main lock,
+	// for better parallelization.
+	freeListMu sync.Mutex
+}
+
+var logging = loggingT{
+	out:       os.Stderr,
+	formatter: GlogFormatter{},
+}
+
+// getBuffer returns a new, ready-to-use buffer.
+func (l *loggingT) getBuffer() *buffer {
+	l.freeListMu.Lock()
+	b := l.freeList
+	if b != nil {
+		l.freeList = b.next
+	}
+	l.freeListMu.Unlock()
+	if b == nil {
+		b = new(buffer)
+	} else {
+		b.next = nil
+		b.Reset()
+	}
+	return b
+}
+
+// putBuffer returns a buffer to the free list.
+func (l *loggingT) putBuffer(b *buffer) {
+	if b.Len() >= 256 {
+		// Let big buffers die a natural death.
+		return
+	}
+	l.freeListMu.Lock()
+	b.next = l.freeList
+	l.freeList = b
+	l.freeListMu.Unlock()
+}
+
+const digits = "0123456789"
+
+// twoDigits formats a zero-prefixed two-digit number into buf starting at
+// position i and returns the number of bytes written.
+func (buf *buffer) twoDigits(i, d int) {
+	buf.tmp[i+1] = digits[d%10]
+	d /= 10
+	buf.tmp[i] = digits[d%10]
+}
+
+func (buf *buffer) nDigits(n, i, d int, pad byte) {
+	j := n - 1
+	for ; j >= 0 && d > 0; j-- {
+		buf.tmp[i+j] = digits[d%10]
+		d /= 10
+	}
+	for ; j >= 0; j-- {
+		buf.tmp[i+j] = pad
+	}
+}
+
+func (buf *buffer) someDigits(i, d int) int {
+	j := len(buf.tmp)
+	for {
+		j--
+		buf.tmp[j] = digits[d%10]
+		d /= 10
+		if d == 0 {
+			break
+		}
+	}
+	return copy(buf.tmp[i:], buf.tmp[j:])
+}
+
+// header formats a log header as defined by the C++ implementation.
+// It returns a buffer containing the formatted header and the user's file
+// and line number. The depth specifies how many stack frames above the
+// logging call to identify as the source of the line.
+//
+//	Lmmdd hh:mm:ss.uuuuuu threadid file:line]
+//
+// where the fields are defined as follows:
+//
+//	L                A single character, representing the log level
+//	                 (eg 'I' for INFO)
+//	mm               The month (zero padded; ie May is '05')
+//	dd               The day (zero padded)
+//	hh:mm:ss.uuuuuu  Time in hours, minutes and fractional seconds
+//	threadid         The space-padded process ID
+//	file             The file name
+//	line             The line number
+func (l *loggingT) header(s severity, depth int) (*buffer, string, int) {
+	file, line := callerFileLine(depth)
+	return l.formatHeader(s, file, line), file, line
+}
+
+// callerFileLine returns the base file name and line number of the logging
+// call that is depth frames above the caller of callerFileLine.
+func callerFileLine(depth int) (string, int) {
+	_, file, line, ok := runtime.Caller(3 + depth)
+	if !ok {
+		return "???", 1
+	}
+	if slash := lastIndexByte(file, '/'); slash >= 0 {
+		file = file[slash+1:]
+	}
+	return file, line
+}
+
+func lastIndexByte(s string, b byte) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatHeader formats a log header using the provided file name and line
+// number.
+func (l *loggingT) formatHeader(s severity, file string, line int) *buffer {
+	buf := l.getBuffer()
+	l.writeGlogHeader(buf, s, file, line, timeNow(), pid)
+	return buf
+}
+
+// writeGlogHeader appends the classic glog header to buf.
+func (l *loggingT) writeGlogHeader(buf *buffer, s severity, file string, line int, now time.Time, procID int) {
+	if line < 0 {
+		line = 0 // not a real line number, but acceptable to the user
+	}
+	if s > fatalLog {
+		s = infoLog // for safety.
+	}
+
+	// Avoid Fprintf, for speed. The format is so simple that we can do it
+	// by hand. This is most macro-logging, so allocations matter.
+	_, month, day := now.Date()
+	hour, minute, second := now.Clock()
+	// Lmmdd hh:mm:ss.uuuuuu threadid file:line]
+	buf.tmp[0] = severityChar[s]
+	buf.twoDigits(1, int(month))
+	buf.twoDigits(3, day)
+	buf.tmp[5] = ' '
+	buf.twoDigits(6, hour)
+	buf.tmp[8] = ':'
+	buf.twoDigits(9, minute)
+	buf.tmp[11] = ':'
+	buf.twoDigits(12, second)
+	buf.tmp[14] = '.'
+	buf.nDigits(6, 15, now.Nanosecond()/1000, '0')
+	buf.tmp[21] = ' '
+	buf.nDigits(7, 22, procID, ' ') // TODO: should be thread ID
+	buf.tmp[29] = ' '
+	buf.Write(buf.tmp[:30])
+	buf.WriteString(file)
+	buf.tmp[0] = ':'
+	n := buf.someDigits(1, line)
+	buf.tmp[n+1] = ']'
+	buf.tmp[n+2] = ' '
+	buf.Write(buf.tmp[:n+3])
+}
+
+// write hands a fully formatted log line to the configured output and
+// records it in the per-severity line counts exposed by Stats.
+func (l *loggingT) write(s severity, b []byte) {
+	atomic.AddInt64(&l.stats[s], 1)
+	l.mu.Lock()
+	_, _ = l.out.Write(b)
+	l.mu.Unlock()
+}
+
+// Output writes an already-formatted log line directly to the output
+// configured by Init, without passing it through a Formatter. It exists
+// for subsystems that produce their own line format, such as
+// glog/accesslog, but still want to share Init's output destination (and
+// therefore its rotation), and to be counted by Stats.
+func Output(sev Severity, line []byte) {
+	logging.write(sev, line)
+}
+
+// println, print, printf build the message, attribute it to the caller via
+// callerFileLine, and hand the pair to the configured Formatter.
+func (l *loggingT) println(s severity, args ...interface{}) {
+	file, line := callerFileLine(0)
+	l.write(s, l.formatter.Format(s, file, line, timeNow(), pid, []byte(fmt.Sprintln(args...))))
+}
+
+func (l *loggingT) print(s severity, args ...interface{}) {
+	l.printDepth(s, 1, args...)
+}
+
+func (l *loggingT) printDepth(s severity, depth int, args ...interface{}) {
+	file, line := callerFileLine(depth)
+	l.write(s, l.formatter.Format(s, file, line, timeNow(), pid, []byte(fmt.Sprint(args...))))
+}
+
+func (l *loggingT) printf(s severity, format string, args ...interface{}) {
+	file, line := callerFileLine(0)
+	l.write(s, l.formatter.Format(s, file, line, timeNow(), pid, []byte(fmt.Sprintf(format, args...))))
+}
+
+// Info logs to the INFO log.
+func Info(args ...interface{}) {
+	logging.print(infoLog, args...)
+}
+
+// InfoDepth acts as Info but uses depth to determine which call frame to log.
+// InfoDepth(0, "msg") is the same as Info("msg").
+func InfoDepth(depth int, args ...interface{}) {
+	logging.printDepth(infoLog, depth, args...)
+}
+
+// Infof logs to the INFO log. Arguments are handled in the manner of fmt.Printf.
+func Infof(format string, args ...interface{}) {
+	logging.printf(infoLog, format, args...)
+}
+
+// Warning logs to the WARNING and INFO logs.
+func Warning(args ...interface{}) {
+	logging.print(warningLog, args...)
+}
+
+// WarningDepth acts as Warning but uses depth to determine which call frame to log.
+func WarningDepth(depth int, args ...interface{}) {
+	logging.printDepth(warningLog, depth, args...)
+}
+
+// Warningf logs to the WARNING and INFO logs. Arguments are handled in the manner of fmt.Printf.
+func Warningf(format string, args ...interface{}) {
+	logging.printf(warningLog, format, args...)
+}
+
+// Error logs to the ERROR, WARNING, and INFO logs.
+func Error(args ...interface{}) {
+	logging.print(errorLog, args...)
+}
+
+// ErrorDepth acts as Error but uses depth to determine which call frame to log.
+func ErrorDepth(depth int, args ...interface{}) {
+	logging.printDepth(errorLog, depth, args...)
+}
+
+// Errorf logs to the ERROR, WARNING, and INFO logs. Arguments are handled in the manner of fmt.Printf.
+func Errorf(format string, args ...interface{}) {
+	logging.printf(errorLog, format, args...)
+}
+
+// Fatal logs to the FATAL, ERROR, WARNING, and INFO logs, then calls os.Exit(1).
+func Fatal(args ...interface{}) {
+	logging.print(fatalLog, args...)
+	os.Exit(1)
+}
+
+// FatalDepth acts as Fatal but uses depth to determine which call frame to log.
+func FatalDepth(depth int, args ...interface{}) {
+	logging.printDepth(fatalLog, depth, args...)
+	os.Exit(1)
+}
+
+// Fatalf logs to the FATAL, ERROR, WARNING, and INFO logs, then calls os.Exit(1).
+func Fatalf(format string, args ...interface{}) {
+	logging.printf(fatalLog, format, args...)
+	os.Exit(1)
+}
+
+// CopyStandardLogTo arranges for messages written to the Go "log" package's
+// default logs to also appear in the Google logs for the named and lower
+// severities. Valid names are "INFO", "WARNING" and "ERROR". Subsequent
+// changes to the standard log's default output location or format may break
+// this behavior.
+func CopyStandardLogTo(name string) {
+	sev, ok := severityByName(name)
+	if !ok {
+		panic(fmt.Sprintf("glog.CopyStandardLogTo(%q): unrecognized severity name", name))
+	}
+	// Set a log format that captures the user's file and line:
+	//   d.go:23: message
+	stdLog.mu.Lock()
+	defer stdLog.mu.Unlock()
+	log.SetFlags(log.Lshortfile)
+	log.SetOutput(logBridge(sev))
+}
+
+func severityByName(s string) (severity, bool) {
+	for i, name := range severityName {
+		if name == s {
+			return severity(i), true
+		}
+	}
+	return 0, false
+}
+
+var stdLog struct {
+	mu sync.Mutex
+}
+
+// logBridge provides the Write method that enables CopyStandardLogTo to connect
+// Go's standard logs to the logs provided by this package.
+type logBridge severity
+
+// Write parses the standard logging line and passes its components to the
+// logger for severity(lb).
+func (lb logBridge) Write(b []byte) (n int, err error) {
+	var (
+		file = "???"
+		line = 1
+		text string
+	)
+	// Split "d.go:23: message" into "d.go", "23", and "message".
+	if parts := bytes.SplitN(b, []byte{':'}, 3); len(parts) != 3 || len(parts[0]) < 1 || len(parts[2]) < 1 {
+		text = fmt.Sprintf("bad log format: %s", b)
+	} else {
+		file = string(parts[0])
+		text = string(parts[2][1:]) // skip leading space
+		fmt.Sscanf(string(parts[1]), "%d", &line)
+	}
+	logging.write(severity(lb), logging.formatter.Format(severity(lb), file, line, timeNow(), pid, []byte(text)))
+	return len(b), nil
+}
+
+// Stats returns the number of lines logged so far at each severity,
+// keyed by severity name (eg "INFO").
+func Stats() map[string]int64 {
+	out := make(map[string]int64, numSeverity)
+	for s := infoLog; int(s) < numSeverity; s++ {
+		out[s.String()] = atomic.LoadInt64(&logging.stats[s])
+	}
+	return out
+}
+
+// Flush flushes all pending log I/O.
+func Flush() {
+	logging.mu.Lock()
+	defer logging.mu.Unlock()
+	if f, ok := logging.out.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := logging.out.(*os.File); ok {
+		_ = f.Sync()
+	}
+}
@@ -31,10 +31,26 @@ import (
 )
 
 func setupBuffer() (*bytes.Buffer, func()) {
+	return setupBufferWithFormatter(GlogFormatter{})
+}
+
+func setupBufferWithFormatter(f Formatter) (*bytes.Buffer, func()) {
 	buf := new(bytes.Buffer)
 	oldOut := logging.out
-	Init(Options{Output: buf})
-	return buf, func() { Init(Options{Output: oldOut}) }
+	oldFormatter := logging.formatter
+	Init(Options{Output: buf, Formatter: f})
+	return buf, func() { Init(Options{Output: oldOut, Formatter: oldFormatter}) }
+}
+
+// formatters is the set of built-in Formatter implementations, used to run
+// the tests below as table tests over each of them.
+var formatters = []struct {
+	name string
+	Formatter
+}{
+	{"glog", GlogFormatter{}},
+	{"json", JSONFormatter{}},
+	{"logfmt", LogfmtFormatter{}},
 }
 
 func hasPrefix(out, prefix string) func() (bool, string) {
@@ -51,16 +67,38 @@ func hasSuffix(out, suffix string) func() (bool, string) {
 	}
 }
 
-// Test that Info works as advertised.
+// Test that Info works as advertised, for every built-in Formatter.
 func TestInfo(t *testing.T) {
-	buf, teardown := setupBuffer()
-	defer teardown()
+	for _, tc := range formatters {
+		t.Run(tc.name, func(t *testing.T) {
+			buf, teardown := setupBufferWithFormatter(tc.Formatter)
+			defer teardown()
+
+			Info("test")
+			out := buf.String()
+			assert.Check(t, is.Contains(out, "glog_test.go"))
+			assertSeverityAndMsg(t, tc.name, out, "INFO", "test")
+		})
+	}
+}
 
-	Info("test")
-	out := buf.String()
-	assert.Check(t, hasPrefix(out, "I"))
-	assert.Check(t, hasSuffix(out, "] test\n"))
-	assert.Check(t, is.Contains(out, "glog_test.go"))
+// assertSeverityAndMsg checks that out carries the given severity and msg in
+// the shape produced by the named formatter.
+func assertSeverityAndMsg(t *testing.T, name, out, severity, msg string) {
+	t.Helper()
+	switch name {
+	case "glog":
+		assert.Check(t, hasPrefix(out, severity[:1]))
+		assert.Check(t, hasSuffix(out, "] "+msg+"\n"))
+	case "json":
+		assert.Check(t, is.Contains(out, fmt.Sprintf(`"level":%q`, severity)))
+		assert.Check(t, is.Contains(out, fmt.Sprintf(`"msg":%q`, msg)))
+	case "logfmt":
+		assert.Check(t, is.Contains(out, "level="+severity))
+		assert.Check(t, is.Contains(out, fmt.Sprintf("msg=%q", msg)))
+	default:
+		t.Fatalf("unknown formatter %q", name)
+	}
 }
 
 func TestInfoDepth(t *testing.T) {
@@ -89,18 +127,22 @@ func nextLineNum() int {
 	return line + 1
 }
 
-// Test that using the standard log package logs to INFO.
+// Test that using the standard log package logs to INFO, for every built-in
+// Formatter.
 func TestStandardLog(t *testing.T) {
-	buf, teardown := setupBuffer()
-	defer teardown()
-	CopyStandardLogTo("INFO")
-	defer func() { log.SetOutput(os.Stderr) }()
-
-	log.Print("test")
-	out := buf.String()
-	assert.Check(t, hasPrefix(out, "I"))
-	assert.Check(t, hasSuffix(out, "] test\n"))
-	assert.Check(t, is.Contains(out, "glog_test.go"))
+	for _, tc := range formatters {
+		t.Run(tc.name, func(t *testing.T) {
+			buf, teardown := setupBufferWithFormatter(tc.Formatter)
+			defer teardown()
+			CopyStandardLogTo("INFO")
+			defer func() { log.SetOutput(os.Stderr) }()
+
+			log.Print("test")
+			out := buf.String()
+			assert.Check(t, is.Contains(out, "glog_test.go"))
+			assertSeverityAndMsg(t, tc.name, out, "INFO", "test")
+		})
+	}
 }
 
 func patchTimeNow() func() {
@@ -111,18 +153,32 @@ func patchTimeNow() func() {
 	return func() { timeNow = old }
 }
 
-// Test that the header has the correct format.
+// Test that the header has the correct format, for every built-in Formatter.
 func TestHeader(t *testing.T) {
-	buf, teardown := setupBuffer()
-	defer teardown()
-	defer patchTimeNow()()
-
-	pid = 1234
-	line := nextLineNum()
-	Info("test")
-
-	expected := fmt.Sprintf("I0102 15:04:05.067890    1234 glog_test.go:%d] test\n", line)
-	assert.Equal(t, expected, buf.String())
+	for _, tc := range formatters {
+		t.Run(tc.name, func(t *testing.T) {
+			buf, teardown := setupBufferWithFormatter(tc.Formatter)
+			defer teardown()
+			defer patchTimeNow()()
+
+			pid = 1234
+			line := nextLineNum()
+			Info("test")
+			out := buf.String()
+
+			switch tc.name {
+			case "glog":
+				expected := fmt.Sprintf("I0102 15:04:05.067890    1234 glog_test.go:%d] test\n", line)
+				assert.Equal(t, expected, out)
+			case "json":
+				assert.Check(t, is.Contains(out, fmt.Sprintf(`"caller":"glog_test.go:%d"`, line)))
+				assert.Check(t, is.Contains(out, `"pid":1234`))
+			case "logfmt":
+				assert.Check(t, is.Contains(out, fmt.Sprintf("caller=glog_test.go:%d", line)))
+				assert.Check(t, is.Contains(out, "pid=1234"))
+			}
+		})
+	}
 }
 
 func TestError(t *testing.T) {
@@ -142,3 +198,10 @@ func BenchmarkHeader(b *testing.B) {
 		logging.putBuffer(buf)
 	}
 }
+
+func BenchmarkHeaderJSON(b *testing.B) {
+	f := JSONFormatter{}
+	for i := 0; i < b.N; i++ {
+		_ = f.Format(infoLog, "glog_test.go", 42, timeNow(), pid, nil)
+	}
+}
@@ -0,0 +1,111 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileOptions configures rotation of the log file written by Init, when set
+// on Options.File. It is a thin wrapper around lumberjack.Logger.
+type FileOptions struct {
+	// Path is the file to write logs to. Rotation is disabled unless Path
+	// is set.
+	Path string
+
+	// MaxSizeMB is the maximum size, in megabytes, of the log file before
+	// it gets rotated. It defaults to 100 megabytes.
+	MaxSizeMB int
+
+	// MaxBackups is the maximum number of old log files to retain. The
+	// default is to retain all old log files.
+	MaxBackups int
+
+	// MaxAgeDays is the maximum number of days to retain old log files,
+	// based on the timestamp encoded in the file name. By default old log
+	// files are not removed based on age.
+	MaxAgeDays int
+
+	// Compress determines whether rotated log files are compressed with
+	// gzip.
+	Compress bool
+
+	// LocalTime determines whether the timestamp encoded in rotated log
+	// file names is the computer's local time. The default is UTC.
+	LocalTime bool
+}
+
+// SyncCloser is returned by Init so callers can flush and release the
+// configured output before exiting.
+type SyncCloser interface {
+	Sync() error
+	Close() error
+}
+
+// noopSyncCloser is returned by Init when no file rotation was configured,
+// so callers can always defer handle.Close() without a nil check.
+type noopSyncCloser struct{}
+
+func (noopSyncCloser) Sync() error  { return nil }
+func (noopSyncCloser) Close() error { return nil }
+
+// rotatingFile adapts a *lumberjack.Logger to SyncCloser.
+type rotatingFile struct {
+	*lumberjack.Logger
+}
+
+// Sync is a no-op: lumberjack writes directly to the open file and does not
+// expose an fsync hook.
+func (*rotatingFile) Sync() error { return nil }
+
+// newRotatingOutput builds the lumberjack.Logger described by fo, along with
+// the SyncCloser handle returned to callers of Init.
+func newRotatingOutput(fo FileOptions) (*lumberjack.Logger, *rotatingFile) {
+	lj := &lumberjack.Logger{
+		Filename:   fo.Path,
+		MaxSize:    fo.MaxSizeMB,
+		MaxBackups: fo.MaxBackups,
+		MaxAge:     fo.MaxAgeDays,
+		Compress:   fo.Compress,
+		LocalTime:  fo.LocalTime,
+	}
+	return lj, &rotatingFile{lj}
+}
+
+// watchReopen rotates lj whenever the process receives SIGHUP, until the
+// returned cancel function is called.
+func watchReopen(lj *lumberjack.Logger) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				_ = lj.Rotate()
+			case <-done:
+				signal.Stop(sigCh)
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,62 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gotestyourself/gotestyourself/assert"
+)
+
+// TestRotation fills a log file past Options.File.MaxSizeMB and asserts that
+// lumberjack rotated it into a backup file alongside the original.
+func TestRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glog-rotation")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "test.log")
+	handle := Init(Options{File: FileOptions{Path: logPath, MaxSizeMB: 1}})
+	defer func() {
+		assert.NilError(t, handle.Close())
+		Init(Options{Output: os.Stderr})
+	}()
+
+	line := strings.Repeat("x", 1024)
+	for i := 0; i < 1100; i++ { // ~1100 * 1KB > 1MB
+		Info(line)
+	}
+	assert.NilError(t, handle.Sync())
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Check(t, hasRotatedBackup(entries))
+}
+
+// hasRotatedBackup reports whether entries contains more than the original
+// log file, ie lumberjack rotated at least one backup alongside it.
+func hasRotatedBackup(entries []os.FileInfo) func() (bool, string) {
+	return func() (bool, string) {
+		msg := fmt.Sprintf("expected a rotated backup file, got %v", entries)
+		return len(entries) > 1, msg
+	}
+}
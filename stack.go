@@ -0,0 +1,33 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import "runtime"
+
+// stacks returns the formatted stack trace of the calling goroutine, or of
+// all goroutines when all is true.
+func stacks(all bool) []byte {
+	n := 1 << 16
+	for {
+		buf := make([]byte, n)
+		size := runtime.Stack(buf, all)
+		if size < len(buf) {
+			return buf[:size]
+		}
+		n *= 2
+	}
+}
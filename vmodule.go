@@ -0,0 +1,161 @@
+// Go support for leveled logs, analogous to https://code.google.com/p/google-glog/
+//
+// Copyright 2013 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is a verbosity level, as used by V. Higher levels are more verbose.
+type Level int32
+
+// globalLevel is the verbosity threshold applied to every V call that does
+// not match a more specific entry in vmodule.
+var globalLevel int32
+
+// vmodulePat is a single "pattern=level" entry parsed from a vmodule spec.
+type vmodulePat struct {
+	pattern string
+	level   Level
+}
+
+// vmodule holds the current vmodule pattern list. It is replaced wholesale
+// by SetVmodule, so V can read it without holding a lock.
+var vmodule atomic.Value // []vmodulePat
+
+func init() {
+	vmodule.Store([]vmodulePat(nil))
+}
+
+// SetVerbosity sets the global verbosity threshold used by V.
+func SetVerbosity(level Level) {
+	atomic.StoreInt32(&globalLevel, int32(level))
+}
+
+// Verbosity returns the current global verbosity threshold.
+func Verbosity() Level {
+	return Level(atomic.LoadInt32(&globalLevel))
+}
+
+// SetVmodule replaces the vmodule pattern list from a comma-separated spec
+// of "pattern=level" pairs, eg "gopher*=3,mypkg=1". pattern is matched
+// against the base name of the logging call's source file, using
+// filepath.Match syntax.
+func SetVmodule(spec string) error {
+	pats, err := parseVmodule(spec)
+	if err != nil {
+		return err
+	}
+	vmodule.Store(pats)
+	return nil
+}
+
+// VmoduleString renders the current vmodule pattern list back into the
+// "pattern=level,..." form accepted by SetVmodule.
+func VmoduleString() string {
+	pats, _ := vmodule.Load().([]vmodulePat)
+	parts := make([]string, len(pats))
+	for i, p := range pats {
+		parts[i] = fmt.Sprintf("%s=%d", p.pattern, p.level)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseVmodule(spec string) ([]vmodulePat, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	entries := strings.Split(spec, ",")
+	pats := make([]vmodulePat, 0, len(entries))
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("glog: invalid vmodule entry %q", entry)
+		}
+		level, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("glog: invalid vmodule level in %q: %v", entry, err)
+		}
+		pats = append(pats, vmodulePat{pattern: kv[0], level: Level(level)})
+	}
+	return pats, nil
+}
+
+// Verbose is returned by V and indicates whether a call is enabled at the
+// requested verbosity level.
+type Verbose bool
+
+// V reports whether logging at the given level is enabled, either because
+// it does not exceed the global verbosity threshold set by SetVerbosity,
+// or because a vmodule pattern set by SetVmodule matches the caller's file
+// at this level or higher.
+func V(level Level) Verbose {
+	if Level(atomic.LoadInt32(&globalLevel)) >= level {
+		return true
+	}
+	pats, _ := vmodule.Load().([]vmodulePat)
+	if len(pats) == 0 {
+		return false
+	}
+	_, file, _, ok := runtime.Caller(1)
+	if !ok {
+		return false
+	}
+	if slash := strings.LastIndexByte(file, '/'); slash >= 0 {
+		file = file[slash+1:]
+	}
+	file = strings.TrimSuffix(file, filepath.Ext(file))
+	for _, p := range pats {
+		if level > p.level {
+			continue
+		}
+		if matched, _ := filepath.Match(p.pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Info logs to the INFO log if v is true. Arguments are handled in the
+// manner of fmt.Print.
+func (v Verbose) Info(args ...interface{}) {
+	if v {
+		logging.print(infoLog, args...)
+	}
+}
+
+// Infoln logs to the INFO log if v is true. Arguments are handled in the
+// manner of fmt.Println.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v {
+		logging.println(infoLog, args...)
+	}
+}
+
+// Infof logs to the INFO log if v is true. Arguments are handled in the
+// manner of fmt.Printf.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		logging.printf(infoLog, format, args...)
+	}
+}